@@ -0,0 +1,165 @@
+// Package absent generates PrometheusRule objects containing absent()
+// alerts for the vector selectors referenced by a user-workload
+// PrometheusRule's alerting rules, so that a metric silently disappearing is
+// itself alertable.
+package absent
+
+import (
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/prometheus/promql/parser"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// NoAlertOnAbsenceAnnotation opts an individual alerting rule out of
+	// absent() generation when set to any value.
+	NoAlertOnAbsenceAnnotation = "monitoring.openshift.io/no-alert-on-absence"
+
+	// OwnedRuleNameSuffix is appended to a source PrometheusRule's name to
+	// produce the name of its generated absent-metrics PrometheusRule.
+	OwnedRuleNameSuffix = "-absent-metrics"
+
+	absentRuleGroupName   = "absent-metrics"
+	absentAlertFor        = "10m"
+	absentAlertSeverity   = "info"
+	absentAlertNamePrefix = "AbsentMetric_"
+
+	prometheusRuleAPIVersion = "monitoring.coreos.com/v1"
+	prometheusRuleKind       = "PrometheusRule"
+)
+
+// GenerateAbsentAlertRuleFor returns the PrometheusRule that should exist for
+// src's generated absent() alerts, owned by src via an owner reference. It
+// returns nil if src has no alert worth generating an absent() rule for -
+// e.g. it contains only recording rules, or every alert opted out via
+// NoAlertOnAbsenceAnnotation.
+func GenerateAbsentAlertRuleFor(src *monitoringv1.PrometheusRule) (*monitoringv1.PrometheusRule, error) {
+	var rules []monitoringv1.Rule
+	for _, group := range src.Spec.Groups {
+		for _, rule := range group.Rules {
+			if rule.Alert == "" {
+				continue // Record rules are not walked for absent() alerts.
+			}
+			if _, ok := rule.Annotations[NoAlertOnAbsenceAnnotation]; ok {
+				continue
+			}
+
+			selectors, err := distinctVectorSelectors(rule.Expr.StrVal)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse expr of alert %q in PrometheusRule %s/%s: %w", rule.Alert, src.Namespace, src.Name, err)
+			}
+
+			for _, selector := range selectors {
+				rules = append(rules, absentRuleFor(rule, selector))
+			}
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	return &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            src.Name + OwnedRuleNameSuffix,
+			Namespace:       src.Namespace,
+			Labels:          copyLabels(src.Labels),
+			OwnerReferences: []metav1.OwnerReference{ownerReference(src)},
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name:  absentRuleGroupName,
+					Rules: rules,
+				},
+			},
+		},
+	}, nil
+}
+
+func absentRuleFor(alert monitoringv1.Rule, selector string) monitoringv1.Rule {
+	labels := make(map[string]string, len(alert.Labels)+1)
+	for k, v := range alert.Labels {
+		labels[k] = v
+	}
+	labels["severity"] = absentAlertSeverity
+
+	return monitoringv1.Rule{
+		Alert:  absentAlertNamePrefix + alert.Alert,
+		Expr:   intstr.FromString(fmt.Sprintf("absent(%s)", selector)),
+		For:    absentAlertFor,
+		Labels: labels,
+	}
+}
+
+func ownerReference(src *monitoringv1.PrometheusRule) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion:         prometheusRuleAPIVersion,
+		Kind:               prometheusRuleKind,
+		Name:               src.Name,
+		UID:                src.UID,
+		Controller:         boolPtr(true),
+		BlockOwnerDeletion: boolPtr(true),
+	}
+}
+
+// distinctVectorSelectors parses expr and returns the distinct vector
+// selectors it references, in the order first seen, skipping any selector
+// that is already wrapped in absent() or absent_over_time().
+func distinctVectorSelectors(expr string) ([]string, error) {
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	var selectors []string
+
+	parser.Inspect(node, func(n parser.Node, path []parser.Node) error {
+		if _, ok := n.(*parser.VectorSelector); !ok {
+			return nil
+		}
+		if insideAbsentCall(path) {
+			return nil
+		}
+
+		s := n.String()
+		if _, ok := seen[s]; ok {
+			return nil
+		}
+		seen[s] = struct{}{}
+		selectors = append(selectors, s)
+		return nil
+	})
+
+	return selectors, nil
+}
+
+func insideAbsentCall(path []parser.Node) bool {
+	for _, n := range path {
+		call, ok := n.(*parser.Call)
+		if !ok || call.Func == nil {
+			continue
+		}
+		if call.Func.Name == "absent" || call.Func.Name == "absent_over_time" {
+			return true
+		}
+	}
+	return false
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	out := make(map[string]string, len(labels))
+	for k, v := range labels {
+		out[k] = v
+	}
+	return out
+}
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,158 @@
+package absent
+
+import (
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDistinctVectorSelectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "single selector",
+			expr: `version{namespace="ns",job="prometheus-example-app"} == 1`,
+			want: []string{`version{job="prometheus-example-app",namespace="ns"}`},
+		},
+		{
+			name: "duplicate selectors are deduped",
+			expr: `up{job="a"} + up{job="a"}`,
+			want: []string{`up{job="a"}`},
+		},
+		{
+			name: "distinct selectors are kept in first-seen order",
+			expr: `up{job="a"} + up{job="b"}`,
+			want: []string{`up{job="a"}`, `up{job="b"}`},
+		},
+		{
+			name: "selector already wrapped in absent() is skipped",
+			expr: `absent(up{job="a"})`,
+			want: nil,
+		},
+		{
+			name: "selector already wrapped in absent_over_time() is skipped",
+			expr: `absent_over_time(up{job="a"}[5m])`,
+			want: nil,
+		},
+		{
+			name:    "invalid expr returns an error",
+			expr:    `this is not promql`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := distinctVectorSelectors(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateAbsentAlertRuleFor(t *testing.T) {
+	src := &monitoringv1.PrometheusRule{
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name: "example",
+					Rules: []monitoringv1.Rule{
+						{
+							Record: "version:blah:count",
+							Expr:   intstr.FromString(`count(version)`),
+						},
+						{
+							Alert: "VersionAlert",
+							Expr:  intstr.FromString(`version{namespace="ns",job="prometheus-example-app"} == 1`),
+						},
+						{
+							Alert:       "OptedOutAlert",
+							Expr:        intstr.FromString(`up{job="a"} == 0`),
+							Annotations: map[string]string{NoAlertOnAbsenceAnnotation: "true"},
+						},
+					},
+				},
+			},
+		},
+	}
+	src.Name = "prometheus-example-rule"
+	src.Namespace = "user-workload-test"
+	src.Labels = map[string]string{"k8s-app": "prometheus-example-rule"}
+
+	got, err := GenerateAbsentAlertRuleFor(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil {
+		t.Fatalf("expected a generated PrometheusRule, got nil")
+	}
+
+	if got.Name != src.Name+OwnedRuleNameSuffix {
+		t.Errorf("got name %q, want %q", got.Name, src.Name+OwnedRuleNameSuffix)
+	}
+	if got.Namespace != src.Namespace {
+		t.Errorf("got namespace %q, want %q", got.Namespace, src.Namespace)
+	}
+
+	// The record rule and the opted-out alert must not produce any
+	// absent() rules - only VersionAlert's selector should.
+	var rules []monitoringv1.Rule
+	for _, g := range got.Spec.Groups {
+		rules = append(rules, g.Rules...)
+	}
+	if len(rules) != 1 {
+		t.Fatalf("got %d generated rules, want 1: %+v", len(rules), rules)
+	}
+	if rules[0].Alert != "AbsentMetric_VersionAlert" {
+		t.Errorf("got alert name %q, want %q", rules[0].Alert, "AbsentMetric_VersionAlert")
+	}
+
+	// The generated labels must be a copy, not an alias of src.Labels.
+	got.Labels["k8s-app"] = "mutated"
+	if src.Labels["k8s-app"] != "prometheus-example-rule" {
+		t.Fatalf("mutating the generated rule's labels mutated src.Labels: %v", src.Labels)
+	}
+}
+
+func TestGenerateAbsentAlertRuleForNoAlerts(t *testing.T) {
+	src := &monitoringv1.PrometheusRule{
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name: "example",
+					Rules: []monitoringv1.Rule{
+						{Record: "version:blah:count", Expr: intstr.FromString(`count(version)`)},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := GenerateAbsentAlertRuleFor(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("expected nil for a PrometheusRule with no alerting rules, got %+v", got)
+	}
+}
@@ -0,0 +1,96 @@
+package absent
+
+import (
+	"context"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringclientv1 "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/typed/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Controller watches user-workload PrometheusRules and keeps their
+// generated absent-metrics PrometheusRule in sync via Reconciler. CMO's
+// operator should construct one with NewController alongside its other
+// user-workload informers and call Run from its main reconcile loop.
+type Controller struct {
+	reconciler *Reconciler
+	informer   cache.SharedIndexInformer
+}
+
+// NewControllerForClient returns a Controller with its own SharedIndexInformer
+// over PrometheusRules in namespace, built from client. Use this when the
+// caller has no informer factory of its own to share, e.g. CMO's operator
+// start-up for the user-workload PrometheusRules it watches.
+func NewControllerForClient(client monitoringclientv1.MonitoringV1Interface, namespace string, resync time.Duration) *Controller {
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				return client.PrometheusRules(namespace).List(context.Background(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				return client.PrometheusRules(namespace).Watch(context.Background(), opts)
+			},
+		},
+		&monitoringv1.PrometheusRule{},
+		resync,
+		cache.Indexers{},
+	)
+	return NewController(client, informer)
+}
+
+// NewController returns a Controller that reconciles PrometheusRules served
+// by informer using client to manage the owned absent-metrics rules.
+func NewController(client monitoringclientv1.MonitoringV1Interface, informer cache.SharedIndexInformer) *Controller {
+	c := &Controller{
+		reconciler: NewReconciler(client),
+		informer:   informer,
+	}
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueue,
+		UpdateFunc: func(_, new interface{}) { c.enqueue(new) },
+		DeleteFunc: c.enqueueDeleted,
+	})
+
+	return c
+}
+
+// Run starts the informer and blocks until stopCh is closed, reconciling
+// user-workload PrometheusRules as add/update/delete notifications arrive.
+func (c *Controller) Run(stopCh <-chan struct{}) {
+	go c.informer.Run(stopCh)
+	cache.WaitForCacheSync(stopCh, c.informer.HasSynced)
+	<-stopCh
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	rule, ok := obj.(*monitoringv1.PrometheusRule)
+	if !ok {
+		return
+	}
+	if err := c.reconciler.ReconcileSource(context.Background(), rule); err != nil {
+		klog.Errorf("failed to reconcile absent-metrics rule for PrometheusRule %s/%s: %v", rule.Namespace, rule.Name, err)
+	}
+}
+
+func (c *Controller) enqueueDeleted(obj interface{}) {
+	rule, ok := obj.(*monitoringv1.PrometheusRule)
+	if !ok {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			rule, ok = tombstone.Obj.(*monitoringv1.PrometheusRule)
+			if !ok {
+				return
+			}
+		} else {
+			return
+		}
+	}
+	if err := c.reconciler.DeleteForSource(context.Background(), rule); err != nil {
+		klog.Errorf("failed to delete absent-metrics rule for PrometheusRule %s/%s: %v", rule.Namespace, rule.Name, err)
+	}
+}
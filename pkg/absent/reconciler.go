@@ -0,0 +1,66 @@
+package absent
+
+import (
+	"context"
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringclientv1 "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/typed/monitoring/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reconciler keeps each user-workload PrometheusRule's generated
+// absent-metrics PrometheusRule in sync with its source.
+type Reconciler struct {
+	Client monitoringclientv1.MonitoringV1Interface
+}
+
+// NewReconciler returns a Reconciler that manages owned absent-metrics
+// PrometheusRules through client.
+func NewReconciler(client monitoringclientv1.MonitoringV1Interface) *Reconciler {
+	return &Reconciler{Client: client}
+}
+
+// ReconcileSource creates, updates, or deletes the absent-metrics
+// PrometheusRule owned by src so that it matches what
+// GenerateAbsentAlertRuleFor(src) currently derives. Call it whenever src is
+// created or updated.
+func (r *Reconciler) ReconcileSource(ctx context.Context, src *monitoringv1.PrometheusRule) error {
+	wanted, err := GenerateAbsentAlertRuleFor(src)
+	if err != nil {
+		return err
+	}
+
+	rules := r.Client.PrometheusRules(src.Namespace)
+	ownedName := src.Name + OwnedRuleNameSuffix
+
+	existing, err := rules.Get(ctx, ownedName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if wanted == nil {
+			return nil
+		}
+		_, err = rules.Create(ctx, wanted, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return fmt.Errorf("failed to get owned absent-metrics rule %s/%s: %w", src.Namespace, ownedName, err)
+	case wanted == nil:
+		return rules.Delete(ctx, existing.Name, metav1.DeleteOptions{})
+	default:
+		wanted.ResourceVersion = existing.ResourceVersion
+		_, err = rules.Update(ctx, wanted, metav1.UpdateOptions{})
+		return err
+	}
+}
+
+// DeleteForSource deletes the absent-metrics PrometheusRule owned by src, if
+// any. Call it when src itself is deleted and no owner-reference garbage
+// collection is expected to run (e.g. in tests against a fake client).
+func (r *Reconciler) DeleteForSource(ctx context.Context, src *monitoringv1.PrometheusRule) error {
+	err := r.Client.PrometheusRules(src.Namespace).Delete(ctx, src.Name+OwnedRuleNameSuffix, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
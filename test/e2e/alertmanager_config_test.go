@@ -0,0 +1,232 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/cluster-monitoring-operator/test/e2e/framework"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	tenantWebhookReceiverName = "tenant-webhook"
+	isolatedTestNs            = "user-workload-test-isolated"
+)
+
+// TestUserWorkloadTenantAlertmanagerConfig proves that a tenant-owned
+// AlertmanagerConfig created in UserWorkloadTestNs is merged into the
+// user-workload Alertmanager's generated configuration and routes alerts to
+// a tenant-owned receiver, while a namespace that doesn't own a matching
+// AlertmanagerConfig is not affected.
+func TestUserWorkloadTenantAlertmanagerConfig(t *testing.T) {
+	f.SetupUserWorkloadAssets(t)
+	t.Cleanup(func() {
+		deleteWebhookReceiver(t, framework.UserWorkloadTestNs)
+		f.AssertDeletionOfUserWorkloadAssets()(t)
+	})
+
+	uwmCM := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      userWorkloadMonitorConfigMapName,
+			Namespace: f.UserWorkloadMonitoringNs,
+		},
+		Data: map[string]string{
+			"config.yaml": `alertmanager:
+  enabled: true
+`,
+		},
+	}
+	f.MustCreateOrUpdateConfigMap(t, uwmCM)
+
+	amc := &monitoringv1alpha1.AlertmanagerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tenant-routing",
+			Namespace: framework.UserWorkloadTestNs,
+		},
+		Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+			Route: &monitoringv1alpha1.Route{
+				Receiver: tenantWebhookReceiverName,
+				Matchers: []monitoringv1alpha1.Matcher{
+					{Name: "namespace", Value: framework.UserWorkloadTestNs, MatchType: monitoringv1alpha1.MatchEqual},
+				},
+			},
+			Receivers: []monitoringv1alpha1.Receiver{
+				{
+					Name: tenantWebhookReceiverName,
+					WebhookConfigs: []monitoringv1alpha1.WebhookConfig{
+						{
+							URL: stringPtr(fmt.Sprintf("http://%s.%s.svc:8080/", webhookReceiverName, framework.UserWorkloadTestNs)),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		scenarios []scenario
+	}{
+		{
+			name: "Test tenant AlertmanagerConfig is merged and routes alerts",
+			scenarios: []scenario{
+				{"assert user workload alertmanager exists and rollout", f.AssertStatefulSetExistsAndRollout("alertmanager-user-workload", f.UserWorkloadMonitoringNs)},
+				{"start absent-metrics controller", f.StartAbsentMetricsController},
+				{"deploy tenant webhook receiver", deployWebhookReceiver},
+				{"setup user application with tenant alertmanager config", func(t *testing.T) { f.SetupUserApplicationWithAlertmanagerConfig(t, amc) }},
+				{"assert absent-metric rule visible", f.AssertAbsentMetricRuleVisible("VersionAlert")},
+				{"assert alertmanager config merged", f.AssertAlertmanagerConfigMerged(framework.UserWorkloadTestNs, tenantWebhookReceiverName)},
+				{"assert tenant webhook received the alert", assertWebhookReceivedAlert},
+			},
+		},
+		{
+			name: "Test AlertmanagerConfig in another namespace is not merged",
+			scenarios: []scenario{
+				{"create isolated namespace", createIsolatedNamespace},
+				{"create alertmanager config in isolated namespace", createIsolatedAlertmanagerConfig},
+				{"assert isolated alertmanager config is not merged", f.AssertAlertmanagerConfigNotMerged(isolatedTestNs, "isolated-receiver")},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			for _, scenario := range tt.scenarios {
+				t.Run(scenario.name, scenario.assertion)
+			}
+		})
+	}
+}
+
+const webhookReceiverName = "tenant-webhook-receiver"
+
+func deployWebhookReceiver(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := f.KubeClient.AppsV1().Deployments(framework.UserWorkloadTestNs).Create(ctx, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: webhookReceiverName,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(1),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": webhookReceiverName},
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": webhookReceiverName},
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name:  webhookReceiverName,
+							Image: "docker.io/mendhak/http-https-echo:29",
+							Env: []v1.EnvVar{
+								{Name: "HTTP_PORT", Value: "8080"},
+							},
+							Ports: []v1.ContainerPort{
+								{ContainerPort: 8080},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = f.KubeClient.CoreV1().Services(framework.UserWorkloadTestNs).Create(ctx, &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: webhookReceiverName,
+		},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": webhookReceiverName},
+			Ports: []v1.ServicePort{
+				{Name: "web", Port: 8080, TargetPort: intstr.FromInt(8080)},
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func deleteWebhookReceiver(t *testing.T, ns string) {
+	ctx := context.Background()
+	_ = f.KubeClient.AppsV1().Deployments(ns).Delete(ctx, webhookReceiverName, metav1.DeleteOptions{})
+	_ = f.KubeClient.CoreV1().Services(ns).Delete(ctx, webhookReceiverName, metav1.DeleteOptions{})
+}
+
+// assertWebhookReceivedAlert asserts that the tenant webhook receiver logged
+// an incoming request carrying the VersionAlert payload, proving Alertmanager
+// actually delivered the alert to the tenant-owned receiver rather than just
+// merging a route for it.
+func assertWebhookReceivedAlert(t *testing.T) {
+	ctx := context.Background()
+	err := framework.Poll(5*time.Second, 5*time.Minute, func() error {
+		pods, err := f.KubeClient.CoreV1().Pods(framework.UserWorkloadTestNs).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("app=%s", webhookReceiverName),
+		})
+		if err != nil {
+			return err
+		}
+		if len(pods.Items) == 0 {
+			return fmt.Errorf("tenant webhook receiver pod not found yet")
+		}
+
+		logs, err := f.KubeClient.CoreV1().Pods(framework.UserWorkloadTestNs).GetLogs(pods.Items[0].Name, &v1.PodLogOptions{}).DoRaw(ctx)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(string(logs), "VersionAlert") {
+			return fmt.Errorf("tenant webhook receiver has not logged a delivered VersionAlert notification yet")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func createIsolatedNamespace(t *testing.T) {
+	_, err := f.KubeClient.CoreV1().Namespaces().Create(context.Background(), &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: isolatedTestNs},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func createIsolatedAlertmanagerConfig(t *testing.T) {
+	amc := &monitoringv1alpha1.AlertmanagerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "isolated-routing",
+			Namespace: isolatedTestNs,
+		},
+		Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+			Route: &monitoringv1alpha1.Route{
+				Receiver: "isolated-receiver",
+				Matchers: []monitoringv1alpha1.Matcher{
+					{Name: "namespace", Value: isolatedTestNs, MatchType: monitoringv1alpha1.MatchEqual},
+				},
+			},
+			Receivers: []monitoringv1alpha1.Receiver{
+				{Name: "isolated-receiver"},
+			},
+		},
+	}
+	f.MustCreateAlertmanagerConfig(t, amc)
+}
+
+func stringPtr(s string) *string { return &s }
+func int32Ptr(v int32) *int32    { return &v }
@@ -0,0 +1,212 @@
+package framework
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	thanosQuerierRouteName      = "thanos-querier"
+	thanosQuerierRouteNamespace = "openshift-monitoring"
+
+	defaultAlertSettlingPeriod = 5 * time.Minute
+	defaultAlertLookbackWindow = time.Minute
+)
+
+// MutedAlerts lists alerts that tests are allowed to leave firing without
+// failing AssertNoCriticalAlertsFiring. A test that intentionally triggers an
+// alert as part of its own assertions (e.g. AdditionalTestAlertRule) should
+// add the alert name here rather than skip the post-run check entirely.
+var MutedAlerts = []string{
+	"AdditionalTestAlertRule",
+}
+
+// AssertNoCriticalAlertsFiring returns an assertion, suitable for t.Cleanup,
+// that fails the test if any critical alert is firing on the cluster at the
+// end of a test run. It queries the in-cluster thanos-querier route for
+// ALERTS{alertstate="firing",severity="critical"}, ignoring Watchdog/AlwaysOn
+// and any alert listed in MutedAlerts, and prints the labels of any offending
+// alert.
+//
+// sleep is how long to wait before querying so that alerts have time to
+// settle into their final state; it defaults to 5m when <= 0. window is the
+// PromQL range over which ALERTS is evaluated; it defaults to 1m when <= 0.
+func (f *Framework) AssertNoCriticalAlertsFiring(sleep, window time.Duration) func(t *testing.T) {
+	return func(t *testing.T) {
+		t.Helper()
+
+		offending, err := f.findFiringCriticalAlerts(sleep, window)
+		if err != nil {
+			t.Fatalf("failed to query thanos-querier for firing critical alerts: %v", err)
+		}
+		if len(offending) == 0 {
+			return
+		}
+
+		for _, labels := range offending {
+			t.Logf("critical alert firing at end of test: %v", labels)
+		}
+		t.Fatalf("test left %d critical alert(s) firing, see log above", len(offending))
+	}
+}
+
+// RunTestsAssertingNoCriticalAlerts calls m.Run() and, when the
+// CMO_E2E_ASSERT_NO_CRITICAL_ALERTS environment variable is set, follows a
+// successful run with the same check as AssertNoCriticalAlertsFiring. It is
+// an opt-in TestMain hook for upgrade-style test binaries that want
+// automatic post-run critical-alert coverage across the whole suite, rather
+// than every test wiring up its own t.Cleanup.
+func (f *Framework) RunTestsAssertingNoCriticalAlerts(m *testing.M) int {
+	code := m.Run()
+	if code != 0 {
+		return code
+	}
+	if os.Getenv("CMO_E2E_ASSERT_NO_CRITICAL_ALERTS") == "" {
+		return code
+	}
+
+	offending, err := f.findFiringCriticalAlerts(0, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to query thanos-querier for firing critical alerts: %v\n", err)
+		return 1
+	}
+	for _, labels := range offending {
+		fmt.Fprintf(os.Stderr, "critical alert firing at end of test run: %v\n", labels)
+	}
+	if len(offending) > 0 {
+		return 1
+	}
+	return code
+}
+
+// findFiringCriticalAlerts sleeps for the settling period and returns the
+// label sets of any unmuted critical alert firing over the preceding window.
+// sleep and window fall back to their package defaults when <= 0.
+func (f *Framework) findFiringCriticalAlerts(sleep, window time.Duration) ([]map[string]string, error) {
+	if sleep <= 0 {
+		sleep = defaultAlertSettlingPeriod
+	}
+	if window <= 0 {
+		window = defaultAlertLookbackWindow
+	}
+
+	time.Sleep(sleep)
+
+	query := fmt.Sprintf(`ALERTS{alertstate="firing",severity="critical",alertname!~"Watchdog|AlwaysOn"}[%s]`, window)
+
+	result, err := f.queryThanosQuerier(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var offending []map[string]string
+	for _, sample := range result {
+		if isMutedAlert(sample["alertname"]) {
+			continue
+		}
+		offending = append(offending, sample)
+	}
+	return offending, nil
+}
+
+// thanosQuerierBearerToken mints a short-lived token for the prometheus-k8s
+// service account, which is bound to a cluster role allowing it to read
+// metrics through thanos-querier.
+func (f *Framework) thanosQuerierBearerToken() (string, error) {
+	tr, err := f.KubeClient.CoreV1().ServiceAccounts(thanosQuerierRouteNamespace).CreateToken(
+		context.Background(),
+		"prometheus-k8s",
+		&authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{
+				ExpirationSeconds: toInt64(600),
+			},
+		},
+		metav1.CreateOptions{},
+	)
+	if err != nil {
+		return "", err
+	}
+	return tr.Status.Token, nil
+}
+
+func isMutedAlert(alertname string) bool {
+	for _, muted := range MutedAlerts {
+		if muted == alertname {
+			return true
+		}
+	}
+	return false
+}
+
+// queryThanosQuerier issues promQuery against the cluster's thanos-querier
+// route and returns the label sets of the resulting series.
+func (f *Framework) queryThanosQuerier(promQuery string) ([]map[string]string, error) {
+	route, err := f.OpenShiftRouteClient.Routes(thanosQuerierRouteNamespace).Get(context.Background(), thanosQuerierRouteName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s route: %w", thanosQuerierRouteName, err)
+	}
+
+	token, err := f.thanosQuerierBearerToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bearer token for prometheus-k8s: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://%s/api/v1/query", route.Spec.Host), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	q := req.URL.Query()
+	q.Set("query", promQuery)
+	req.URL.RawQuery = q.Encode()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d querying thanos-querier: %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp struct {
+		Status string `json:"status"`
+		Data   struct {
+			Result []struct {
+				Metric map[string]string `json:"metric"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal thanos-querier response: %w", err)
+	}
+	if apiResp.Status != "success" {
+		return nil, fmt.Errorf("thanos-querier query was not successful: %s", string(body))
+	}
+
+	labelSets := make([]map[string]string, 0, len(apiResp.Data.Result))
+	for _, r := range apiResp.Data.Result {
+		labelSets = append(labelSets, r.Metric)
+	}
+	return labelSets, nil
+}
@@ -4,13 +4,11 @@ import (
 	"context"
 	"fmt"
 	"testing"
-	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -77,14 +75,7 @@ func (f *Framework) TearDownUserApplication(t *testing.T) {
 		return
 	}
 
-	err = Poll(time.Second, 5*time.Minute, func() error {
-		err = f.KubeClient.CoreV1().Namespaces().Delete(context.TODO(), UserWorkloadTestNs, metav1.DeleteOptions{})
-		if err != nil {
-			return err
-		}
-		return nil
-	})
-
+	err = DeleteK8sObjectWithRetry(f.KubeClient.CoreV1().Namespaces().Delete, UserWorkloadTestNs)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -96,12 +87,12 @@ func (f *Framework) TearDownUserApplication(t *testing.T) {
 
 func (f *Framework) deployUserApplication(t *testing.T) error {
 	t.Helper()
-	_, err := f.KubeClient.CoreV1().Namespaces().Create(ctx, &v1.Namespace{
+	_, err := CreateK8sObjectWithRetry(f.KubeClient.CoreV1().Namespaces().Create, f.KubeClient.CoreV1().Namespaces().Get, &v1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: UserWorkloadTestNs,
 		},
-	}, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
+	})
+	if err != nil {
 		return err
 	}
 
@@ -109,7 +100,7 @@ func (f *Framework) deployUserApplication(t *testing.T) error {
 		return f.KubeClient.CoreV1().Namespaces().Get(ctx, UserWorkloadTestNs, metav1.GetOptions{})
 	})
 
-	app, err := f.KubeClient.AppsV1().Deployments(UserWorkloadTestNs).Create(ctx, &appsv1.Deployment{
+	app, err := CreateK8sObjectWithRetry(f.KubeClient.AppsV1().Deployments(UserWorkloadTestNs).Create, f.KubeClient.AppsV1().Deployments(UserWorkloadTestNs).Get, &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "prometheus-example-app",
 		},
@@ -136,12 +127,12 @@ func (f *Framework) deployUserApplication(t *testing.T) error {
 				},
 			},
 		},
-	}, metav1.CreateOptions{})
+	})
 	if err != nil {
 		return err
 	}
 
-	_, err = f.KubeClient.CoreV1().Services(UserWorkloadTestNs).Create(ctx, &v1.Service{
+	_, err = CreateK8sObjectWithRetry(f.KubeClient.CoreV1().Services(UserWorkloadTestNs).Create, f.KubeClient.CoreV1().Services(UserWorkloadTestNs).Get, &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "prometheus-example-app",
 			Labels: map[string]string{
@@ -162,9 +153,12 @@ func (f *Framework) deployUserApplication(t *testing.T) error {
 			},
 			Type: v1.ServiceTypeClusterIP,
 		},
-	}, metav1.CreateOptions{})
+	})
+	if err != nil {
+		return err
+	}
 
-	_, err = f.MonitoringClient.ServiceMonitors(UserWorkloadTestNs).Create(ctx, &monitoringv1.ServiceMonitor{
+	_, err = CreateK8sObjectWithRetry(f.MonitoringClient.ServiceMonitors(UserWorkloadTestNs).Create, f.MonitoringClient.ServiceMonitors(UserWorkloadTestNs).Get, &monitoringv1.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "prometheus-example-monitor",
 			Labels: map[string]string{
@@ -185,12 +179,12 @@ func (f *Framework) deployUserApplication(t *testing.T) error {
 				},
 			},
 		},
-	}, metav1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
+	})
+	if err != nil {
 		return err
 	}
 
-	_, err = f.MonitoringClient.PrometheusRules(UserWorkloadTestNs).Create(ctx, &monitoringv1.PrometheusRule{
+	_, err = CreateK8sObjectWithRetry(f.MonitoringClient.PrometheusRules(UserWorkloadTestNs).Create, f.MonitoringClient.PrometheusRules(UserWorkloadTestNs).Get, &monitoringv1.PrometheusRule{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "prometheus-example-rule",
 			Labels: map[string]string{
@@ -215,12 +209,12 @@ func (f *Framework) deployUserApplication(t *testing.T) error {
 				},
 			},
 		},
-	}, metav1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
+	})
+	if err != nil {
 		return err
 	}
 
-	_, err = f.MonitoringClient.PrometheusRules(UserWorkloadTestNs).Create(ctx, &monitoringv1.PrometheusRule{
+	_, err = CreateK8sObjectWithRetry(f.MonitoringClient.PrometheusRules(UserWorkloadTestNs).Create, f.MonitoringClient.PrometheusRules(UserWorkloadTestNs).Get, &monitoringv1.PrometheusRule{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "prometheus-example-rule-leaf",
 			Labels: map[string]string{
@@ -241,8 +235,8 @@ func (f *Framework) deployUserApplication(t *testing.T) error {
 				},
 			},
 		},
-	}, metav1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
+	})
+	if err != nil {
 		return err
 	}
 
@@ -255,30 +249,32 @@ func (f *Framework) deployUserApplication(t *testing.T) error {
 
 func (f *Framework) createPrometheusAlertmanagerInUserNamespace(t *testing.T) error {
 	t.Helper()
-	_, err := f.MonitoringClient.Alertmanagers(UserWorkloadTestNs).Create(ctx, &monitoringv1.Alertmanager{
+	_, err := CreateK8sObjectWithRetry(f.MonitoringClient.Alertmanagers(UserWorkloadTestNs).Create, f.MonitoringClient.Alertmanagers(UserWorkloadTestNs).Get, &monitoringv1.Alertmanager{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "not-to-be-reconciled",
 		},
 		Spec: monitoringv1.AlertmanagerSpec{
 			Replicas: toInt32(1),
 		},
-	}, metav1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
+	})
+	if err != nil {
 		return err
 	}
 
-	_, err = f.MonitoringClient.Prometheuses(UserWorkloadTestNs).Create(ctx, &monitoringv1.Prometheus{
+	_, err = CreateK8sObjectWithRetry(f.MonitoringClient.Prometheuses(UserWorkloadTestNs).Create, f.MonitoringClient.Prometheuses(UserWorkloadTestNs).Get, &monitoringv1.Prometheus{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "not-to-be-reconciled",
 		},
 		Spec: monitoringv1.PrometheusSpec{
 			Replicas: toInt32(1),
 		},
-	}, metav1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
+	})
+	if err != nil {
 		return err
 	}
 	return nil
 }
 
 func toInt32(v int32) *int32 { return &v }
+
+func toInt64(v int64) *int64 { return &v }
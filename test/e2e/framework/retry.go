@@ -0,0 +1,101 @@
+package framework
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultRetryInterval = 5 * time.Second
+	defaultRetryTimeout  = 2 * time.Minute
+)
+
+// CreateK8sObjectWithRetry creates obj by calling create, retrying transient
+// errors (webhook not ready, connection reset, ...) with Poll up to
+// defaultRetryTimeout. Create is treated as idempotent: on an AlreadyExists
+// error it calls get to fetch the canonical server object instead of
+// returning the caller's local obj, which is missing server-populated
+// fields such as Namespace when the typed client derives it from the
+// clientset's own namespace scoping rather than ObjectMeta.
+func CreateK8sObjectWithRetry[T metav1.Object](
+	create func(ctx context.Context, obj T, opts metav1.CreateOptions) (T, error),
+	get func(ctx context.Context, name string, opts metav1.GetOptions) (T, error),
+	obj T,
+) (T, error) {
+	result := obj
+	err := Poll(defaultRetryInterval, defaultRetryTimeout, func() error {
+		created, err := create(context.Background(), obj, metav1.CreateOptions{})
+		if err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				existing, getErr := get(context.Background(), obj.GetName(), metav1.GetOptions{})
+				if getErr != nil {
+					return getErr
+				}
+				result = existing
+				return nil
+			}
+			return err
+		}
+		result = created
+		return nil
+	})
+	return result, err
+}
+
+// GetK8sObjectWithRetry calls get, retrying transient errors with Poll up to
+// defaultRetryTimeout.
+func GetK8sObjectWithRetry[T any](get func(ctx context.Context, name string, opts metav1.GetOptions) (T, error), name string) (T, error) {
+	var result T
+	err := Poll(defaultRetryInterval, defaultRetryTimeout, func() error {
+		got, err := get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		result = got
+		return nil
+	})
+	return result, err
+}
+
+// DeleteK8sObjectWithRetry calls del, retrying transient errors with Poll up
+// to defaultRetryTimeout. Delete is treated as idempotent: a NotFound error
+// is not retried and is reported as success.
+func DeleteK8sObjectWithRetry(del func(ctx context.Context, name string, opts metav1.DeleteOptions) error, name string) error {
+	return Poll(defaultRetryInterval, defaultRetryTimeout, func() error {
+		err := del(context.Background(), name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+// UpdateK8sObjectWithRetry updates an object, retrying transient errors with
+// Poll up to defaultRetryTimeout. On every attempt - including after a
+// Conflict - it refetches the current object via get and reapplies mutate to
+// it before calling update, so callers get a correct read-modify-write retry
+// loop instead of resubmitting a stale resourceVersion.
+func UpdateK8sObjectWithRetry[T any](
+	get func(ctx context.Context) (T, error),
+	mutate func(obj T),
+	update func(ctx context.Context, obj T) (T, error),
+) (T, error) {
+	var result T
+	err := Poll(defaultRetryInterval, defaultRetryTimeout, func() error {
+		current, err := get(context.Background())
+		if err != nil {
+			return err
+		}
+		mutate(current)
+		updated, err := update(context.Background(), current)
+		if err != nil {
+			return err
+		}
+		result = updated
+		return nil
+	})
+	return result, err
+}
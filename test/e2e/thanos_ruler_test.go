@@ -18,6 +18,7 @@ import (
 func TestUserWorkloadThanosRulerWithAdditionalAlertmanagers(t *testing.T) {
 	f.SetupUserWorkloadAssets(t)
 	t.Cleanup(func() {
+		f.AssertNoCriticalAlertsFiring(0, 0)(t)
 		f.AssertDeletionOfUserWorkloadAssets()(t)
 		deleteAlertmanager(t)
 	})